@@ -0,0 +1,395 @@
+package memory
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+	bolt "go.etcd.io/bbolt"
+)
+
+// sessionsBucket is the top-level BoltDB bucket that holds one sub-bucket
+// per session. Each session sub-bucket maps sequential, big-endian uint64
+// keys to JSON-encoded item envelopes, so iterating the bucket in key order
+// reproduces insertion order.
+var sessionsBucket = []byte("sessions")
+
+// itemEnvelope is the stable on-disk representation of a result.RunItem. The
+// Type field acts as a discriminator so items can be decoded back into their
+// concrete type without relying on Go's reflection or gob encoding, keeping
+// the BoltDB file readable and forward compatible.
+type itemEnvelope struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+const (
+	itemTypeMessage    = "message"
+	itemTypeToolCall   = "tool_call"
+	itemTypeToolResult = "tool_result"
+	itemTypeHandoff    = "handoff"
+	itemTypeSummary    = "summary"
+)
+
+// BoltOption configures a BoltStorage instance.
+type BoltOption func(*BoltStorage)
+
+// WithBoltTimeout sets how long BoltStorage waits to acquire the file lock
+// when opening the database. The default is bbolt's own default (no timeout).
+func WithBoltTimeout(timeout time.Duration) BoltOption {
+	return func(b *BoltStorage) {
+		b.timeout = timeout
+	}
+}
+
+// WithBoltFileMode sets the file mode used when creating the BoltDB file.
+// The default is 0600.
+func WithBoltFileMode(mode os.FileMode) BoltOption {
+	return func(b *BoltStorage) {
+		b.fileMode = mode
+	}
+}
+
+// BoltStorage implements Memory on top of an embedded BoltDB (bbolt) file.
+// Every session gets its own bucket so sessions can be listed, sized and
+// cleared independently, and history survives process restarts.
+type BoltStorage struct {
+	db       *bolt.DB
+	timeout  time.Duration
+	fileMode os.FileMode
+}
+
+// NewBoltStorage opens (creating if necessary) a BoltDB file at path and
+// returns a Memory implementation backed by it. The caller is responsible
+// for calling Close when done with the returned storage.
+func NewBoltStorage(path string, opts ...BoltOption) (*BoltStorage, error) {
+	b := &BoltStorage{
+		fileMode: 0600,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	db, err := bolt.Open(path, b.fileMode, &bolt.Options{Timeout: b.timeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt database at %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(sessionsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize sessions bucket: %w", err)
+	}
+
+	b.db = db
+	return b, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (b *BoltStorage) Close() error {
+	return b.db.Close()
+}
+
+// Add adds a run result to memory for a specific session
+func (b *BoltStorage) Add(ctx context.Context, sessionID string, runResult *result.RunResult) error {
+	if runResult == nil {
+		return fmt.Errorf("run result cannot be nil")
+	}
+	if sessionID == "" {
+		return fmt.Errorf("session ID cannot be empty")
+	}
+
+	items := make([]result.RunItem, 0, len(runResult.NewItems)+1)
+	items = append(items, runResult.NewItems...)
+	if runResult.FinalOutput != nil {
+		items = append(items, &result.MessageItem{
+			Role:    "assistant",
+			Content: runResult.FinalOutput.(string),
+		})
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		sessions := tx.Bucket(sessionsBucket)
+		bucket, err := sessions.CreateBucketIfNotExists([]byte(sessionID))
+		if err != nil {
+			return fmt.Errorf("failed to open session bucket: %w", err)
+		}
+
+		for _, item := range items {
+			envelope, err := encodeItem(item)
+			if err != nil {
+				return err
+			}
+
+			seq, err := bucket.NextSequence()
+			if err != nil {
+				return fmt.Errorf("failed to allocate item sequence: %w", err)
+			}
+
+			if err := bucket.Put(sequenceKey(seq), envelope); err != nil {
+				return fmt.Errorf("failed to store item: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Get retrieves memory items based on criteria for a specific session
+func (b *BoltStorage) Get(ctx context.Context, sessionID string, criteria *GetCriteria) ([]result.RunItem, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("session ID cannot be empty")
+	}
+
+	if criteria == nil {
+		criteria = &GetCriteria{}
+	}
+
+	var typeFilter map[string]bool
+	if len(criteria.ItemTypes) > 0 {
+		typeFilter = make(map[string]bool, len(criteria.ItemTypes))
+		for _, t := range criteria.ItemTypes {
+			typeFilter[t] = true
+		}
+	}
+
+	var items []result.RunItem
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		sessions := tx.Bucket(sessionsBucket)
+		bucket := sessions.Bucket([]byte(sessionID))
+		if bucket == nil {
+			return nil // no items for this session
+		}
+
+		cursor := bucket.Cursor()
+
+		visit := func(k, v []byte) (stop bool, err error) {
+			item, err := decodeItem(v)
+			if err != nil {
+				return false, err
+			}
+
+			if typeFilter != nil && !typeFilter[item.GetType()] {
+				return false, nil
+			}
+
+			if criteria.AgentName != "" {
+				if handoffItem, ok := item.(*result.HandoffItem); ok {
+					if handoffItem.AgentName != criteria.AgentName {
+						return false, nil
+					}
+				}
+			}
+
+			items = append(items, item)
+
+			// Stop as soon as we have enough items, avoiding a full bucket
+			// scan. Since the caller only asked for the most recent (or
+			// oldest) N items, every later entry in iteration order would be
+			// discarded anyway.
+			if criteria.Limit > 0 && len(items) >= criteria.Limit {
+				return true, nil
+			}
+
+			return false, nil
+		}
+
+		if criteria.Reverse {
+			for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+				stop, err := visit(k, v)
+				if err != nil {
+					return err
+				}
+				if stop {
+					break
+				}
+			}
+		} else {
+			for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+				stop, err := visit(k, v)
+				if err != nil {
+					return err
+				}
+				if stop {
+					break
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		items = []result.RunItem{}
+	}
+	return items, nil
+}
+
+// Clear clears all memory for a specific session
+func (b *BoltStorage) Clear(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return fmt.Errorf("session ID cannot be empty")
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		sessions := tx.Bucket(sessionsBucket)
+		if sessions.Bucket([]byte(sessionID)) == nil {
+			return nil
+		}
+		return sessions.DeleteBucket([]byte(sessionID))
+	})
+}
+
+// ClearAll clears all memory for all sessions
+func (b *BoltStorage) ClearAll(ctx context.Context) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(sessionsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(sessionsBucket)
+		return err
+	})
+}
+
+// Size returns the number of items in memory for a specific session
+func (b *BoltStorage) Size(ctx context.Context, sessionID string) (int, error) {
+	if sessionID == "" {
+		return 0, fmt.Errorf("session ID cannot be empty")
+	}
+
+	var count int
+	err := b.db.View(func(tx *bolt.Tx) error {
+		sessions := tx.Bucket(sessionsBucket)
+		bucket := sessions.Bucket([]byte(sessionID))
+		if bucket == nil {
+			return nil
+		}
+		count = bucket.Stats().KeyN
+		return nil
+	})
+	return count, err
+}
+
+// GetSessions returns all active session IDs
+func (b *BoltStorage) GetSessions(ctx context.Context) ([]string, error) {
+	var sessionIDs []string
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		sessions := tx.Bucket(sessionsBucket)
+		return sessions.ForEachBucket(func(name []byte) error {
+			sessionIDs = append(sessionIDs, string(name))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sessionIDs == nil {
+		sessionIDs = []string{}
+	}
+	return sessionIDs, nil
+}
+
+// GetAll returns all items in memory for a specific session
+func (b *BoltStorage) GetAll(ctx context.Context, sessionID string) ([]result.RunItem, error) {
+	return b.Get(ctx, sessionID, &GetCriteria{})
+}
+
+// GetRecent returns the most recent items for a specific session
+func (b *BoltStorage) GetRecent(ctx context.Context, sessionID string, limit int) ([]result.RunItem, error) {
+	return b.Get(ctx, sessionID, &GetCriteria{
+		Limit:   limit,
+		Reverse: true,
+	})
+}
+
+// GetByType returns items of specific types for a specific session
+func (b *BoltStorage) GetByType(ctx context.Context, sessionID string, itemTypes []string) ([]result.RunItem, error) {
+	return b.Get(ctx, sessionID, &GetCriteria{
+		ItemTypes: itemTypes,
+	})
+}
+
+// sequenceKey encodes a BoltDB bucket sequence number as a fixed-width,
+// lexicographically ordered big-endian key so cursor iteration matches
+// insertion order.
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+// encodeItem wraps a result.RunItem in a type-discriminated JSON envelope.
+func encodeItem(item result.RunItem) ([]byte, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal item: %w", err)
+	}
+
+	envelope := itemEnvelope{
+		Type: item.GetType(),
+		Data: data,
+	}
+
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal item envelope: %w", err)
+	}
+	return encoded, nil
+}
+
+// decodeItem reverses encodeItem, reconstructing the concrete result.RunItem
+// from its type discriminator.
+func decodeItem(data []byte) (result.RunItem, error) {
+	var envelope itemEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal item envelope: %w", err)
+	}
+
+	switch envelope.Type {
+	case itemTypeMessage:
+		var item result.MessageItem
+		if err := json.Unmarshal(envelope.Data, &item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal message item: %w", err)
+		}
+		return &item, nil
+	case itemTypeToolCall:
+		var item result.ToolCallItem
+		if err := json.Unmarshal(envelope.Data, &item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tool call item: %w", err)
+		}
+		return &item, nil
+	case itemTypeToolResult:
+		var item result.ToolResultItem
+		if err := json.Unmarshal(envelope.Data, &item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tool result item: %w", err)
+		}
+		return &item, nil
+	case itemTypeHandoff:
+		var item result.HandoffItem
+		if err := json.Unmarshal(envelope.Data, &item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal handoff item: %w", err)
+		}
+		return &item, nil
+	case itemTypeSummary:
+		var item result.SummaryItem
+		if err := json.Unmarshal(envelope.Data, &item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal summary item: %w", err)
+		}
+		return &item, nil
+	default:
+		return nil, fmt.Errorf("unknown item type %q", envelope.Type)
+	}
+}
@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+)
+
+func TestBoltStorage_Basic(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+
+	storage, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create bolt storage: %v", err)
+	}
+	defer storage.Close()
+
+	sessionID := "test-session"
+
+	size, err := storage.Size(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("Failed to get size: %v", err)
+	}
+	if size != 0 {
+		t.Errorf("Expected size 0, got %d", size)
+	}
+
+	runResult := &result.RunResult{
+		Input: "test input",
+		NewItems: []result.RunItem{
+			&result.MessageItem{Role: "user", Content: "Hello"},
+			&result.MessageItem{Role: "assistant", Content: "Hi there!"},
+		},
+		FinalOutput: "Hi there!",
+	}
+
+	if err := storage.Add(ctx, sessionID, runResult); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	size, err = storage.Size(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("Failed to get size: %v", err)
+	}
+	if size != 3 {
+		t.Errorf("Expected size 3, got %d", size)
+	}
+
+	items, err := storage.GetAll(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("Failed to get all items: %v", err)
+	}
+	if len(items) != 3 {
+		t.Errorf("Expected 3 items, got %d", len(items))
+	}
+
+	msgItem, ok := items[0].(*result.MessageItem)
+	if !ok {
+		t.Fatalf("Expected first item to be a MessageItem, got %T", items[0])
+	}
+	if msgItem.Content != "Hello" {
+		t.Errorf("Expected first item content 'Hello', got %q", msgItem.Content)
+	}
+}
+
+func TestBoltStorage_RestartPersistence(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+	sessionID := "alice-session"
+
+	storage, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create bolt storage: %v", err)
+	}
+
+	runResult := &result.RunResult{
+		NewItems: []result.RunItem{
+			&result.MessageItem{Role: "user", Content: "My name is Alice"},
+			&result.ToolCallItem{Name: "lookup", Parameters: map[string]interface{}{"q": "alice"}},
+			&result.ToolResultItem{Name: "lookup", Result: "found"},
+			&result.HandoffItem{AgentName: "Specialist", Input: "handle billing"},
+		},
+	}
+	if err := storage.Add(ctx, sessionID, runResult); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	// Simulate a process restart: close and reopen the same file.
+	if err := storage.Close(); err != nil {
+		t.Fatalf("Failed to close storage: %v", err)
+	}
+
+	reopened, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen bolt storage: %v", err)
+	}
+	defer reopened.Close()
+
+	items, err := reopened.GetAll(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("Failed to get all items after restart: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("Expected 4 items after restart, got %d", len(items))
+	}
+
+	if items[0].GetType() != "message" {
+		t.Errorf("Expected first item type 'message', got %q", items[0].GetType())
+	}
+	if items[1].GetType() != "tool_call" {
+		t.Errorf("Expected second item type 'tool_call', got %q", items[1].GetType())
+	}
+	if items[2].GetType() != "tool_result" {
+		t.Errorf("Expected third item type 'tool_result', got %q", items[2].GetType())
+	}
+	if items[3].GetType() != "handoff" {
+		t.Errorf("Expected fourth item type 'handoff', got %q", items[3].GetType())
+	}
+
+	handoffItem, ok := items[3].(*result.HandoffItem)
+	if !ok || handoffItem.AgentName != "Specialist" {
+		t.Errorf("Handoff item did not round-trip correctly: %+v", items[3])
+	}
+
+	recent, err := reopened.GetRecent(ctx, sessionID, 2)
+	if err != nil {
+		t.Fatalf("Failed to get recent items: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("Expected 2 recent items, got %d", len(recent))
+	}
+	if recent[0].GetType() != "handoff" {
+		t.Errorf("Expected first recent item to be 'handoff', got %q", recent[0].GetType())
+	}
+}
+
+func TestBoltStorage_SessionIsolation(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+
+	storage, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create bolt storage: %v", err)
+	}
+	defer storage.Close()
+
+	sessionID1 := "session-1"
+	sessionID2 := "session-2"
+
+	if err := storage.Add(ctx, sessionID1, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "Session 1 message"}},
+	}); err != nil {
+		t.Fatalf("Failed to add to session 1: %v", err)
+	}
+
+	if err := storage.Add(ctx, sessionID2, &result.RunResult{
+		NewItems: []result.RunItem{
+			&result.MessageItem{Role: "user", Content: "Session 2 message"},
+			&result.MessageItem{Role: "assistant", Content: "Session 2 response"},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to add to session 2: %v", err)
+	}
+
+	size1, err := storage.Size(ctx, sessionID1)
+	if err != nil || size1 != 1 {
+		t.Errorf("Expected session 1 size 1, got %d (err: %v)", size1, err)
+	}
+
+	size2, err := storage.Size(ctx, sessionID2)
+	if err != nil || size2 != 2 {
+		t.Errorf("Expected session 2 size 2, got %d (err: %v)", size2, err)
+	}
+
+	if err := storage.Clear(ctx, sessionID1); err != nil {
+		t.Fatalf("Failed to clear session 1: %v", err)
+	}
+
+	size1, _ = storage.Size(ctx, sessionID1)
+	if size1 != 0 {
+		t.Errorf("Expected session 1 size 0 after clear, got %d", size1)
+	}
+
+	size2, _ = storage.Size(ctx, sessionID2)
+	if size2 != 2 {
+		t.Errorf("Expected session 2 size still 2 after clearing session 1, got %d", size2)
+	}
+
+	sessions, err := storage.GetSessions(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0] != sessionID2 {
+		t.Errorf("Expected only session %s to remain, got %v", sessionID2, sessions)
+	}
+}
+
+func TestBoltStorage_GetByTypeAndLimit(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+
+	storage, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create bolt storage: %v", err)
+	}
+	defer storage.Close()
+
+	sessionID := "test-session"
+	if err := storage.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{
+			&result.MessageItem{Role: "user", Content: "Message 1"},
+			&result.ToolCallItem{Name: "tool1"},
+			&result.MessageItem{Role: "assistant", Content: "Message 2"},
+			&result.ToolResultItem{Name: "tool1", Result: "result"},
+			&result.HandoffItem{AgentName: "Agent1", Input: "handoff input"},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	messageItems, err := storage.GetByType(ctx, sessionID, []string{"message"})
+	if err != nil {
+		t.Fatalf("Failed to get message items: %v", err)
+	}
+	if len(messageItems) != 2 {
+		t.Errorf("Expected 2 message items, got %d", len(messageItems))
+	}
+
+	limited, err := storage.Get(ctx, sessionID, &GetCriteria{Limit: 3})
+	if err != nil {
+		t.Fatalf("Failed to get limited items: %v", err)
+	}
+	if len(limited) != 3 {
+		t.Errorf("Expected 3 limited items, got %d", len(limited))
+	}
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+)
+
+// Recaller is an optional extension of Memory for implementations that
+// support semantic recall alongside chronological history, such as
+// HybridStorage. Callers that need recall (e.g. runner.Runner) should
+// type-assert for this interface rather than widening Memory itself, since
+// not every backend supports it.
+type Recaller interface {
+	Memory
+	Recall(ctx context.Context, sessionID, query string, k int) ([]result.RunItem, error)
+}
+
+// HybridStorage wraps any Memory implementation and keeps a VectorIndex in
+// sync with it, so callers can recall relevant past items by meaning
+// instead of only by recency. It embeds Memory, so every method of the
+// wrapped implementation remains available; Add, Clear and ClearAll are
+// overridden to also maintain the index.
+type HybridStorage struct {
+	Memory
+
+	index VectorIndex
+}
+
+// NewHybridStorage wraps base with index, embedding each recallable item
+// added to base into index as well.
+func NewHybridStorage(base Memory, index VectorIndex) *HybridStorage {
+	return &HybridStorage{Memory: base, index: index}
+}
+
+// Add adds a run result to the wrapped Memory, then indexes every newly
+// stored MessageItem and ToolResultItem (including the synthesized final
+// response) for semantic recall.
+func (h *HybridStorage) Add(ctx context.Context, sessionID string, runResult *result.RunResult) error {
+	before, err := h.Memory.Size(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Memory.Add(ctx, sessionID, runResult); err != nil {
+		return err
+	}
+
+	after, err := h.Memory.Size(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	added := after - before
+	if added <= 0 {
+		return nil
+	}
+
+	newItems, err := h.Memory.Get(ctx, sessionID, &GetCriteria{Limit: added, Reverse: true})
+	if err != nil {
+		return err
+	}
+
+	// newItems is newest-first; restore chronological order so item IDs are
+	// stable across calls.
+	for i := len(newItems) - 1; i >= 0; i-- {
+		item := newItems[i]
+		text := recallableText(item)
+		if text == "" {
+			continue
+		}
+
+		itemID := fmt.Sprintf("%s#%d", sessionID, before+(len(newItems)-1-i))
+		meta := map[string]interface{}{"type": item.GetType()}
+		if err := h.index.Upsert(ctx, sessionID, itemID, text, meta); err != nil {
+			return fmt.Errorf("failed to index item %s for recall: %w", itemID, err)
+		}
+	}
+
+	return nil
+}
+
+// Clear clears the wrapped Memory and removes the session's entries from
+// the vector index.
+func (h *HybridStorage) Clear(ctx context.Context, sessionID string) error {
+	if err := h.Memory.Clear(ctx, sessionID); err != nil {
+		return err
+	}
+	return h.index.Delete(ctx, sessionID)
+}
+
+// ClearAll clears the wrapped Memory and removes every session's entries
+// from the vector index.
+func (h *HybridStorage) ClearAll(ctx context.Context) error {
+	sessionIDs, err := h.Memory.GetSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Memory.ClearAll(ctx); err != nil {
+		return err
+	}
+
+	for _, sessionID := range sessionIDs {
+		if err := h.index.Delete(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Recall returns the k entries in sessionID most semantically relevant to
+// query, regardless of how long ago they were recorded. Each hit comes back
+// as a new system MessageItem holding the indexed text, not as the item
+// originally stored in the wrapped Memory.
+func (h *HybridStorage) Recall(ctx context.Context, sessionID, query string, k int) ([]result.RunItem, error) {
+	hits, err := h.index.Query(ctx, sessionID, query, k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recall index: %w", err)
+	}
+
+	items := make([]result.RunItem, 0, len(hits))
+	for _, hit := range hits {
+		items = append(items, &result.MessageItem{Role: "system", Content: hit.Text})
+	}
+	return items, nil
+}
+
+// recallableText extracts the text that should be indexed for semantic
+// recall from an item, or "" if the item type carries no recallable text.
+func recallableText(item result.RunItem) string {
+	switch v := item.(type) {
+	case *result.MessageItem:
+		return v.Content
+	case *result.ToolResultItem:
+		return fmt.Sprintf("%v", v.Result)
+	default:
+		return ""
+	}
+}
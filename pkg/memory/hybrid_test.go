@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+)
+
+func TestHybridStorage_RecallAcrossManyTurns(t *testing.T) {
+	ctx := context.Background()
+	sessionID := "alice-session"
+
+	base := NewInMemoryStorage()
+	index := NewInMemoryVectorIndex(wordOverlapEmbedder())
+	hybrid := NewHybridStorage(base, index)
+
+	if err := hybrid.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{
+			&result.MessageItem{Role: "user", Content: "My favorite animal is a dog"},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to add turn 1: %v", err)
+	}
+
+	// Many unrelated turns in between, simulating "five hundred turns ago".
+	for i := 0; i < 20; i++ {
+		if err := hybrid.Add(ctx, sessionID, &result.RunResult{
+			NewItems: []result.RunItem{
+				&result.MessageItem{Role: "user", Content: "What's the weather in Paris?"},
+			},
+		}); err != nil {
+			t.Fatalf("Failed to add filler turn %d: %v", i, err)
+		}
+	}
+
+	recalled, err := hybrid.Recall(ctx, sessionID, "what did I say my favorite animal was", 1)
+	if err != nil {
+		t.Fatalf("Recall failed: %v", err)
+	}
+	if len(recalled) != 1 {
+		t.Fatalf("Expected 1 recalled item, got %d", len(recalled))
+	}
+	msg, ok := recalled[0].(*result.MessageItem)
+	if !ok || msg.Content != "My favorite animal is a dog" {
+		t.Errorf("Expected to recall the dog message, got %+v", recalled[0])
+	}
+}
+
+func TestHybridStorage_ClearRemovesFromIndex(t *testing.T) {
+	ctx := context.Background()
+	sessionID := "test-session"
+
+	base := NewInMemoryStorage()
+	index := NewInMemoryVectorIndex(wordOverlapEmbedder())
+	hybrid := NewHybridStorage(base, index)
+
+	if err := hybrid.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "weather in paris"}},
+	}); err != nil {
+		t.Fatalf("Failed to add: %v", err)
+	}
+
+	if err := hybrid.Clear(ctx, sessionID); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+
+	recalled, err := hybrid.Recall(ctx, sessionID, "weather", 5)
+	if err != nil {
+		t.Fatalf("Recall failed: %v", err)
+	}
+	if len(recalled) != 0 {
+		t.Errorf("Expected no recallable items after Clear, got %d", len(recalled))
+	}
+
+	size, err := base.Size(ctx, sessionID)
+	if err != nil || size != 0 {
+		t.Errorf("Expected underlying Memory to also be cleared, got size %d (err: %v)", size, err)
+	}
+}
+
+func TestHybridStorage_DelegatesUnwrappedMethods(t *testing.T) {
+	ctx := context.Background()
+	sessionID := "test-session"
+
+	base := NewInMemoryStorage()
+	index := NewInMemoryVectorIndex(wordOverlapEmbedder())
+	hybrid := NewHybridStorage(base, index)
+
+	if err := hybrid.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "hello"}},
+	}); err != nil {
+		t.Fatalf("Failed to add: %v", err)
+	}
+
+	// Get is not overridden by HybridStorage, so it should fall through to
+	// the embedded Memory unchanged.
+	items, err := hybrid.Get(ctx, sessionID, nil)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Errorf("Expected 1 item from delegated Get, got %d", len(items))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
 )
@@ -29,6 +30,86 @@ type Memory interface {
 	GetSessions(ctx context.Context) ([]string, error)
 }
 
+// ExpiringMemory is an optional extension of Memory for implementations that
+// support per-session time-to-live and idle expiration. Callers that need
+// eviction control should type-assert for this interface rather than
+// widening Memory itself, since not every backend tracks access times.
+type ExpiringMemory interface {
+	Memory
+
+	// SetSessionTTL overrides the default TTL for a specific session. A zero
+	// ttl disables TTL expiration for that session; idle expiration still
+	// applies.
+	SetSessionTTL(sessionID string, ttl time.Duration) error
+
+	// Touch refreshes a session's last-access time, extending its idle
+	// timeout as if Add/Get had just been called on it. It does not move an
+	// already set TTL deadline.
+	Touch(ctx context.Context, sessionID string) error
+}
+
+// MemoryOptions configures expiration behavior for NewInMemoryStorage.
+type MemoryOptions struct {
+	// DefaultTTL is the time after which a session is evicted regardless of
+	// activity, measured from its first write. Zero disables TTL-based
+	// expiration.
+	DefaultTTL time.Duration
+
+	// IdleTimeout is the time after which a session is evicted if it has
+	// received no Add/Get/Touch calls. Zero disables idle-based expiration.
+	IdleTimeout time.Duration
+
+	// SweepInterval is how often the background eviction sweep runs. If
+	// zero and either DefaultTTL or IdleTimeout is set, it defaults to one
+	// minute.
+	SweepInterval time.Duration
+
+	// OnEvict, if set, is called after a session is removed by the
+	// background sweeper so callers can persist or log its transcript
+	// before it is gone. reason is either "ttl" or "idle".
+	OnEvict func(sessionID string, reason string)
+
+	// clock is overridable by tests so expiration can be exercised without
+	// real sleeps.
+	clock func() time.Time
+}
+
+// MemoryOption mutates a MemoryOptions during construction.
+type MemoryOption func(*MemoryOptions)
+
+// WithDefaultTTL sets MemoryOptions.DefaultTTL.
+func WithDefaultTTL(ttl time.Duration) MemoryOption {
+	return func(o *MemoryOptions) { o.DefaultTTL = ttl }
+}
+
+// WithIdleTimeout sets MemoryOptions.IdleTimeout.
+func WithIdleTimeout(timeout time.Duration) MemoryOption {
+	return func(o *MemoryOptions) { o.IdleTimeout = timeout }
+}
+
+// WithSweepInterval sets MemoryOptions.SweepInterval.
+func WithSweepInterval(interval time.Duration) MemoryOption {
+	return func(o *MemoryOptions) { o.SweepInterval = interval }
+}
+
+// WithOnEvict sets MemoryOptions.OnEvict.
+func WithOnEvict(onEvict func(sessionID string, reason string)) MemoryOption {
+	return func(o *MemoryOptions) { o.OnEvict = onEvict }
+}
+
+// withClock overrides the time source used for expiration. It is unexported
+// because it only exists to let tests substitute a fake clock.
+func withClock(clock func() time.Time) MemoryOption {
+	return func(o *MemoryOptions) { o.clock = clock }
+}
+
+// sessionState tracks expiration bookkeeping for a single session.
+type sessionState struct {
+	lastAccess time.Time
+	expiresAt  time.Time // zero means "no TTL deadline"
+	ttl        time.Duration
+}
+
 // GetCriteria defines criteria for retrieving memory items
 type GetCriteria struct {
 	// Limit limits the number of items to retrieve (0 = no limit)
@@ -48,15 +129,179 @@ type GetCriteria struct {
 type InMemoryStorage struct {
 	mu       sync.RWMutex
 	sessions map[string][]result.RunItem // sessionID -> items
+	state    map[string]*sessionState    // sessionID -> expiration bookkeeping
+
+	opts MemoryOptions
+
+	sweeperOnce sync.Once
+	sweeperWG   sync.WaitGroup
+	stopCh      chan struct{}
 }
 
-// NewInMemoryStorage creates a new in-memory storage instance
-func NewInMemoryStorage() *InMemoryStorage {
+// NewInMemoryStorage creates a new in-memory storage instance. By default
+// sessions never expire; pass WithDefaultTTL/WithIdleTimeout to enable
+// background eviction of stale sessions.
+func NewInMemoryStorage(opts ...MemoryOption) *InMemoryStorage {
+	options := MemoryOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.clock == nil {
+		options.clock = time.Now
+	}
+	if options.SweepInterval == 0 {
+		options.SweepInterval = time.Minute
+	}
+
 	return &InMemoryStorage{
 		sessions: make(map[string][]result.RunItem),
+		state:    make(map[string]*sessionState),
+		opts:     options,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Close stops the background eviction sweeper, if one was started. It is
+// safe to call Close on a storage that never started a sweeper.
+func (m *InMemoryStorage) Close() error {
+	close(m.stopCh)
+	m.sweeperWG.Wait()
+	return nil
+}
+
+// startSweeperLocked lazily launches the background eviction goroutine the
+// first time any session acquires a TTL or idle timeout to enforce — either
+// because MemoryOptions set a global DefaultTTL/IdleTimeout, or because a
+// caller set a per-session deadline via SetSessionTTL. needed should be
+// false when the caller knows no deadline is in play, to avoid starting the
+// sweeper needlessly. Must be called with m.mu held.
+func (m *InMemoryStorage) startSweeperLocked(needed bool) {
+	if !needed {
+		return
+	}
+	m.sweeperOnce.Do(func() {
+		m.sweeperWG.Add(1)
+		go m.sweepLoop()
+	})
+}
+
+func (m *InMemoryStorage) sweepLoop() {
+	defer m.sweeperWG.Done()
+
+	ticker := time.NewTicker(m.opts.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.evictExpired()
+		}
 	}
 }
 
+// evictExpired removes sessions past their TTL or idle deadline and invokes
+// OnEvict for each one outside the lock.
+func (m *InMemoryStorage) evictExpired() {
+	now := m.opts.clock()
+
+	type eviction struct {
+		sessionID string
+		reason    string
+	}
+	var evicted []eviction
+
+	m.mu.Lock()
+	for sessionID, st := range m.state {
+		if reason, expired := m.expiredLocked(st, now); expired {
+			delete(m.sessions, sessionID)
+			delete(m.state, sessionID)
+			evicted = append(evicted, eviction{sessionID: sessionID, reason: reason})
+		}
+	}
+	m.mu.Unlock()
+
+	if m.opts.OnEvict == nil {
+		return
+	}
+	for _, e := range evicted {
+		m.opts.OnEvict(e.sessionID, e.reason)
+	}
+}
+
+// expiredLocked reports whether a session is past its TTL or idle deadline.
+// Must be called with m.mu held.
+func (m *InMemoryStorage) expiredLocked(st *sessionState, now time.Time) (reason string, expired bool) {
+	if !st.expiresAt.IsZero() && !now.Before(st.expiresAt) {
+		return "ttl", true
+	}
+	if m.opts.IdleTimeout > 0 && now.Sub(st.lastAccess) >= m.opts.IdleTimeout {
+		return "idle", true
+	}
+	return "", false
+}
+
+// touchLocked records activity on a session, creating its bookkeeping entry
+// on first use and computing its TTL deadline from either a per-session
+// override or MemoryOptions.DefaultTTL. Must be called with m.mu held.
+func (m *InMemoryStorage) touchLocked(sessionID string, now time.Time) {
+	st, exists := m.state[sessionID]
+	if !exists {
+		st = &sessionState{ttl: m.opts.DefaultTTL}
+		m.state[sessionID] = st
+	}
+
+	st.lastAccess = now
+	if st.ttl > 0 && st.expiresAt.IsZero() {
+		st.expiresAt = now.Add(st.ttl)
+	}
+}
+
+// SetSessionTTL overrides the default TTL for a specific session. A zero ttl
+// disables TTL expiration for that session; idle expiration still applies.
+func (m *InMemoryStorage) SetSessionTTL(sessionID string, ttl time.Duration) error {
+	if sessionID == "" {
+		return fmt.Errorf("session ID cannot be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.opts.clock()
+	st, exists := m.state[sessionID]
+	if !exists {
+		st = &sessionState{lastAccess: now}
+		m.state[sessionID] = st
+	}
+
+	st.ttl = ttl
+	if ttl > 0 {
+		st.expiresAt = now.Add(ttl)
+	} else {
+		st.expiresAt = time.Time{}
+	}
+
+	m.startSweeperLocked(ttl > 0 || m.opts.DefaultTTL > 0 || m.opts.IdleTimeout > 0)
+	return nil
+}
+
+// Touch refreshes a session's last-access time, extending its idle timeout
+// as if Add/Get had just been called on it. It does not move an already set
+// TTL deadline.
+func (m *InMemoryStorage) Touch(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return fmt.Errorf("session ID cannot be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.touchLocked(sessionID, m.opts.clock())
+	m.startSweeperLocked(m.opts.DefaultTTL > 0 || m.opts.IdleTimeout > 0)
+	return nil
+}
+
 // Add adds a run result to memory for a specific session
 func (m *InMemoryStorage) Add(ctx context.Context, sessionID string, runResult *result.RunResult) error {
 	if runResult == nil {
@@ -87,6 +332,9 @@ func (m *InMemoryStorage) Add(ctx context.Context, sessionID string, runResult *
 		})
 	}
 
+	m.touchLocked(sessionID, m.opts.clock())
+	m.startSweeperLocked(m.opts.DefaultTTL > 0 || m.opts.IdleTimeout > 0)
+
 	return nil
 }
 
@@ -96,19 +344,39 @@ func (m *InMemoryStorage) Get(ctx context.Context, sessionID string, criteria *G
 		return nil, fmt.Errorf("session ID cannot be empty")
 	}
 
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
 	if criteria == nil {
 		criteria = &GetCriteria{}
 	}
 
+	// Touching bookkeeping on read requires the write lock, so Get takes a
+	// full lock rather than RLock despite being read-only over m.sessions.
+	// It is released manually (rather than via defer) so the lazy-eviction
+	// path below can call OnEvict outside the lock, consistent with
+	// evictExpired.
+	m.mu.Lock()
+
 	// Get items for the specific session
 	sessionItems, exists := m.sessions[sessionID]
 	if !exists {
+		m.mu.Unlock()
 		return []result.RunItem{}, nil // Return empty slice for non-existent session
 	}
 
+	if st, ok := m.state[sessionID]; ok {
+		if reason, expired := m.expiredLocked(st, m.opts.clock()); expired {
+			delete(m.sessions, sessionID)
+			delete(m.state, sessionID)
+			m.mu.Unlock()
+
+			if m.opts.OnEvict != nil {
+				m.opts.OnEvict(sessionID, reason)
+			}
+			return []result.RunItem{}, nil
+		}
+	}
+
+	m.touchLocked(sessionID, m.opts.clock())
+
 	var filtered []result.RunItem
 
 	// Filter by item types if specified
@@ -157,6 +425,7 @@ func (m *InMemoryStorage) Get(ctx context.Context, sessionID string, criteria *G
 		filtered = filtered[:criteria.Limit]
 	}
 
+	m.mu.Unlock()
 	return filtered, nil
 }
 
@@ -170,6 +439,7 @@ func (m *InMemoryStorage) Clear(ctx context.Context, sessionID string) error {
 	defer m.mu.Unlock()
 
 	delete(m.sessions, sessionID)
+	delete(m.state, sessionID)
 	return nil
 }
 
@@ -179,6 +449,7 @@ func (m *InMemoryStorage) ClearAll(ctx context.Context) error {
 	defer m.mu.Unlock()
 
 	m.sessions = make(map[string][]result.RunItem)
+	m.state = make(map[string]*sessionState)
 	return nil
 }
 
@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+)
+
+// fakeClock is a manually-advanced time source so expiration tests don't
+// depend on real sleeps.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestInMemoryStorage_SessionTTL(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+
+	m := NewInMemoryStorage(WithDefaultTTL(time.Minute), withClock(clock.Now))
+	sessionID := "test-session"
+
+	err := m.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	size, err := m.Size(ctx, sessionID)
+	if err != nil || size != 1 {
+		t.Fatalf("Expected size 1 before expiration, got %d (err: %v)", size, err)
+	}
+
+	clock.Advance(2 * time.Minute)
+
+	items, err := m.Get(ctx, sessionID, nil)
+	if err != nil {
+		t.Fatalf("Failed to get items: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("Expected session to be expired by TTL, got %d items", len(items))
+	}
+}
+
+func TestInMemoryStorage_IdleTimeout(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+
+	m := NewInMemoryStorage(WithIdleTimeout(time.Minute), withClock(clock.Now))
+	sessionID := "test-session"
+
+	if err := m.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "hi"}},
+	}); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	// Touch before the idle timeout elapses; this should reset the clock.
+	clock.Advance(30 * time.Second)
+	if err := m.Touch(ctx, sessionID); err != nil {
+		t.Fatalf("Failed to touch session: %v", err)
+	}
+
+	clock.Advance(30 * time.Second)
+	items, err := m.Get(ctx, sessionID, nil)
+	if err != nil {
+		t.Fatalf("Failed to get items: %v", err)
+	}
+	if len(items) != 1 {
+		t.Errorf("Expected session to survive due to Touch, got %d items", len(items))
+	}
+
+	clock.Advance(2 * time.Minute)
+	items, err = m.Get(ctx, sessionID, nil)
+	if err != nil {
+		t.Fatalf("Failed to get items: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("Expected session to be expired after idle timeout, got %d items", len(items))
+	}
+}
+
+func TestInMemoryStorage_BackgroundSweepEvicts(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+
+	evicted := make(chan string, 1)
+	m := NewInMemoryStorage(
+		WithDefaultTTL(time.Minute),
+		WithSweepInterval(10*time.Millisecond),
+		withClock(clock.Now),
+		WithOnEvict(func(sessionID, reason string) {
+			evicted <- sessionID + ":" + reason
+		}),
+	)
+	defer m.Close()
+
+	sessionID := "test-session"
+	if err := m.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "hi"}},
+	}); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	clock.Advance(2 * time.Minute)
+
+	select {
+	case got := <-evicted:
+		if got != sessionID+":ttl" {
+			t.Errorf("Expected eviction %q, got %q", sessionID+":ttl", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for background sweeper to evict session")
+	}
+}
+
+func TestInMemoryStorage_BackgroundSweepEvictsFromPerSessionTTLOnly(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+
+	// No WithDefaultTTL/WithIdleTimeout here: the only deadline comes from
+	// SetSessionTTL, which must still be enough to start the sweeper.
+	evicted := make(chan string, 1)
+	m := NewInMemoryStorage(
+		WithSweepInterval(10*time.Millisecond),
+		withClock(clock.Now),
+		WithOnEvict(func(sessionID, reason string) {
+			evicted <- sessionID + ":" + reason
+		}),
+	)
+	defer m.Close()
+
+	sessionID := "test-session"
+	if err := m.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "hi"}},
+	}); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	if err := m.SetSessionTTL(sessionID, time.Minute); err != nil {
+		t.Fatalf("Failed to set session TTL: %v", err)
+	}
+
+	clock.Advance(2 * time.Minute)
+
+	select {
+	case got := <-evicted:
+		if got != sessionID+":ttl" {
+			t.Errorf("Expected eviction %q, got %q", sessionID+":ttl", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for background sweeper to evict a session whose only deadline came from SetSessionTTL")
+	}
+}
+
+func TestInMemoryStorage_SetSessionTTLOverride(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+
+	m := NewInMemoryStorage(WithDefaultTTL(time.Hour), withClock(clock.Now))
+	sessionID := "test-session"
+
+	if err := m.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "hi"}},
+	}); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	if err := m.SetSessionTTL(sessionID, 10*time.Second); err != nil {
+		t.Fatalf("Failed to set session TTL: %v", err)
+	}
+
+	clock.Advance(30 * time.Second)
+
+	items, err := m.Get(ctx, sessionID, nil)
+	if err != nil {
+		t.Fatalf("Failed to get items: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("Expected session to respect overridden TTL, got %d items", len(items))
+	}
+}
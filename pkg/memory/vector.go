@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Embedder converts text into a vector embedding for semantic search.
+// Implementations typically call out to an embeddings API, such as the
+// OpenAI text-embedding-3-small provider under
+// pkg/model/providers/openai.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// EmbedderFunc adapts a plain function to the Embedder interface.
+type EmbedderFunc func(ctx context.Context, text string) ([]float32, error)
+
+// Embed implements Embedder.
+func (f EmbedderFunc) Embed(ctx context.Context, text string) ([]float32, error) {
+	return f(ctx, text)
+}
+
+// ScoredItem is a single VectorIndex query hit, together with the
+// similarity score it was ranked by.
+type ScoredItem struct {
+	ItemID string
+	Text   string
+	Meta   map[string]interface{}
+	Score  float32
+}
+
+// VectorIndex stores embedded text per session and supports semantic
+// nearest-neighbor search over it, independent of chronological order.
+type VectorIndex interface {
+	// Upsert embeds text and stores it under itemID within sessionID,
+	// replacing any existing entry with the same itemID.
+	Upsert(ctx context.Context, sessionID, itemID, text string, meta map[string]interface{}) error
+
+	// Query returns the k entries in sessionID most semantically similar to
+	// text, ordered by descending similarity.
+	Query(ctx context.Context, sessionID, text string, k int) ([]ScoredItem, error)
+
+	// Delete removes every entry stored for sessionID.
+	Delete(ctx context.Context, sessionID string) error
+}
+
+// InMemoryVectorIndex is a VectorIndex that keeps embeddings in process
+// memory and ranks queries by exact cosine-similarity top-k. It is suited
+// to development and small sessions; large-scale deployments should back
+// VectorIndex with a dedicated vector database instead.
+type InMemoryVectorIndex struct {
+	mu       sync.RWMutex
+	embedder Embedder
+	sessions map[string]map[string]*vectorEntry // sessionID -> itemID -> entry
+}
+
+type vectorEntry struct {
+	text   string
+	meta   map[string]interface{}
+	vector []float32
+}
+
+// NewInMemoryVectorIndex creates an InMemoryVectorIndex that embeds text
+// with embedder.
+func NewInMemoryVectorIndex(embedder Embedder) *InMemoryVectorIndex {
+	return &InMemoryVectorIndex{
+		embedder: embedder,
+		sessions: make(map[string]map[string]*vectorEntry),
+	}
+}
+
+// Upsert implements VectorIndex.
+func (idx *InMemoryVectorIndex) Upsert(ctx context.Context, sessionID, itemID, text string, meta map[string]interface{}) error {
+	if sessionID == "" {
+		return fmt.Errorf("session ID cannot be empty")
+	}
+	if itemID == "" {
+		return fmt.Errorf("item ID cannot be empty")
+	}
+
+	vector, err := idx.embedder.Embed(ctx, text)
+	if err != nil {
+		return fmt.Errorf("failed to embed item %s: %w", itemID, err)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, exists := idx.sessions[sessionID]; !exists {
+		idx.sessions[sessionID] = make(map[string]*vectorEntry)
+	}
+	idx.sessions[sessionID][itemID] = &vectorEntry{text: text, meta: meta, vector: vector}
+
+	return nil
+}
+
+// Query implements VectorIndex.
+func (idx *InMemoryVectorIndex) Query(ctx context.Context, sessionID, text string, k int) ([]ScoredItem, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("session ID cannot be empty")
+	}
+
+	queryVector, err := idx.embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	entries := idx.sessions[sessionID]
+	scored := make([]ScoredItem, 0, len(entries))
+	for itemID, entry := range entries {
+		scored = append(scored, ScoredItem{
+			ItemID: itemID,
+			Text:   entry.text,
+			Meta:   entry.meta,
+			Score:  cosineSimilarity(queryVector, entry.vector),
+		})
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+
+	if k > 0 && len(scored) > k {
+		scored = scored[:k]
+	}
+
+	return scored, nil
+}
+
+// Delete implements VectorIndex.
+func (idx *InMemoryVectorIndex) Delete(ctx context.Context, sessionID string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	delete(idx.sessions, sessionID)
+	return nil
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 if either
+// vector has zero magnitude or they differ in length.
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
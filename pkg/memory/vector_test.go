@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// wordOverlapEmbedder is a deterministic fake Embedder for tests: it scores
+// a fixed vocabulary by whether each word appears in the text, so texts
+// sharing more words end up with higher cosine similarity.
+func wordOverlapEmbedder() Embedder {
+	vocab := []string{"weather", "paris", "invoice", "billing", "cat", "dog", "animal"}
+	return EmbedderFunc(func(ctx context.Context, text string) ([]float32, error) {
+		lower := strings.ToLower(text)
+		vec := make([]float32, len(vocab))
+		for i, word := range vocab {
+			if strings.Contains(lower, word) {
+				vec[i] = 1
+			}
+		}
+		return vec, nil
+	})
+}
+
+func TestInMemoryVectorIndex_QueryRanksBySimilarity(t *testing.T) {
+	ctx := context.Background()
+	idx := NewInMemoryVectorIndex(wordOverlapEmbedder())
+	sessionID := "test-session"
+
+	if err := idx.Upsert(ctx, sessionID, "1", "The weather in Paris is lovely today", nil); err != nil {
+		t.Fatalf("Failed to upsert item 1: %v", err)
+	}
+	if err := idx.Upsert(ctx, sessionID, "2", "Please send the invoice for billing", nil); err != nil {
+		t.Fatalf("Failed to upsert item 2: %v", err)
+	}
+	if err := idx.Upsert(ctx, sessionID, "3", "My cat and dog are friends", nil); err != nil {
+		t.Fatalf("Failed to upsert item 3: %v", err)
+	}
+
+	hits, err := idx.Query(ctx, sessionID, "what's the billing invoice status", 2)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("Expected 2 hits, got %d", len(hits))
+	}
+	if hits[0].ItemID != "2" {
+		t.Errorf("Expected item 2 to rank first for a billing query, got %s", hits[0].ItemID)
+	}
+}
+
+func TestInMemoryVectorIndex_SessionIsolation(t *testing.T) {
+	ctx := context.Background()
+	idx := NewInMemoryVectorIndex(wordOverlapEmbedder())
+
+	if err := idx.Upsert(ctx, "session-1", "1", "weather in paris", nil); err != nil {
+		t.Fatalf("Failed to upsert into session 1: %v", err)
+	}
+	if err := idx.Upsert(ctx, "session-2", "1", "cat and dog", nil); err != nil {
+		t.Fatalf("Failed to upsert into session 2: %v", err)
+	}
+
+	hits, err := idx.Query(ctx, "session-1", "weather", 5)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Text != "weather in paris" {
+		t.Errorf("Expected session 1 to only see its own entry, got %+v", hits)
+	}
+}
+
+func TestInMemoryVectorIndex_Delete(t *testing.T) {
+	ctx := context.Background()
+	idx := NewInMemoryVectorIndex(wordOverlapEmbedder())
+	sessionID := "test-session"
+
+	if err := idx.Upsert(ctx, sessionID, "1", "weather in paris", nil); err != nil {
+		t.Fatalf("Failed to upsert: %v", err)
+	}
+
+	if err := idx.Delete(ctx, sessionID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	hits, err := idx.Query(ctx, sessionID, "weather", 5)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(hits) != 0 {
+		t.Errorf("Expected no hits after delete, got %d", len(hits))
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	identical := cosineSimilarity([]float32{1, 0, 0}, []float32{1, 0, 0})
+	if identical != 1 {
+		t.Errorf("Expected identical vectors to score 1, got %v", identical)
+	}
+
+	orthogonal := cosineSimilarity([]float32{1, 0}, []float32{0, 1})
+	if orthogonal != 0 {
+		t.Errorf("Expected orthogonal vectors to score 0, got %v", orthogonal)
+	}
+
+	mismatched := cosineSimilarity([]float32{1, 0}, []float32{1, 0, 0})
+	if mismatched != 0 {
+		t.Errorf("Expected mismatched-length vectors to score 0, got %v", mismatched)
+	}
+}
@@ -0,0 +1,272 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+)
+
+// TokenCounter estimates how many tokens an item will consume once rendered
+// into the prompt sent to a model. Implementations are free to use an exact
+// tokenizer or a cheap approximation.
+type TokenCounter func(item result.RunItem) int
+
+// DefaultTokenCounter approximates OpenAI-style tokenization at roughly four
+// characters per token. It is accurate enough for budgeting purposes without
+// pulling in a full tiktoken-compatible BPE tokenizer; callers that need
+// exact counts for a specific model should supply their own TokenCounter.
+func DefaultTokenCounter(item result.RunItem) int {
+	return (len(itemText(item)) + 3) / 4
+}
+
+// itemText extracts the text a TokenCounter or Summarizer should account for
+// from a RunItem, regardless of its concrete type.
+func itemText(item result.RunItem) string {
+	switch v := item.(type) {
+	case *result.MessageItem:
+		return v.Content
+	case *result.ToolCallItem:
+		return fmt.Sprintf("%s(%v)", v.Name, v.Parameters)
+	case *result.ToolResultItem:
+		return fmt.Sprintf("%v", v.Result)
+	case *result.HandoffItem:
+		return v.Input
+	case *result.SummaryItem:
+		return v.Content
+	default:
+		return ""
+	}
+}
+
+// Summarizer condenses a slice of older run items into a short textual
+// summary that can stand in for them in the prompt. A Summarizer is
+// typically backed by an agent.Agent turn or a direct model.Provider
+// completion; wrap either with SummarizerFunc.
+type Summarizer interface {
+	Summarize(ctx context.Context, items []result.RunItem) (string, error)
+}
+
+// SummarizerFunc adapts a plain function to the Summarizer interface.
+type SummarizerFunc func(ctx context.Context, items []result.RunItem) (string, error)
+
+// Summarize implements Summarizer.
+func (f SummarizerFunc) Summarize(ctx context.Context, items []result.RunItem) (string, error) {
+	return f(ctx, items)
+}
+
+// WindowPolicy decides which subset (or compaction) of a session's full
+// history should be sent to the model for the next turn.
+type WindowPolicy interface {
+	// Apply takes the full, chronologically-ordered item history for a
+	// session and returns the items that should actually be replayed to the
+	// model, compacted as needed to respect the policy's own limits.
+	Apply(ctx context.Context, items []result.RunItem) ([]result.RunItem, error)
+}
+
+// Windowed is an optional extension of Memory for implementations that can
+// apply a WindowPolicy directly and persist any resulting compaction, such
+// as InMemoryStorage and BoltStorage. Callers that need windowing (e.g.
+// runner.Runner) should type-assert for this interface rather than widening
+// Memory itself, since not every backend supports it.
+type Windowed interface {
+	Memory
+	GetWindowed(ctx context.Context, sessionID string, policy WindowPolicy) ([]result.RunItem, error)
+}
+
+// SummarizingWindow is a WindowPolicy that keeps the most recent items
+// verbatim up to a reserved tail budget and replaces everything older with a
+// single synthesized system message, so long conversations stay within
+// TokenLimit instead of growing the prompt without bound. A result.SummaryItem
+// that the backward walk over the history reaches within the tail budget is
+// treated as a fixed boundary: it is kept verbatim together with everything
+// after it, so repeated calls don't re-summarize the same history. A
+// SummaryItem lying before the point where the tail budget runs out is
+// passed to the Summarizer along with the other older items.
+type SummarizingWindow struct {
+	// TokenLimit is the maximum total tokens the returned items may consume.
+	TokenLimit int
+
+	// TailBudget is the portion of TokenLimit reserved for the most recent
+	// items, kept verbatim. Defaults to three quarters of TokenLimit when
+	// zero, leaving the remainder for the synthesized summary.
+	TailBudget int
+
+	// CountTokens estimates an item's token cost. Defaults to
+	// DefaultTokenCounter.
+	CountTokens TokenCounter
+
+	// Summarizer produces the replacement text for items older than the
+	// tail window. Required whenever the history actually exceeds
+	// TokenLimit.
+	Summarizer Summarizer
+}
+
+// NewSummarizingWindow creates a SummarizingWindow with sensible defaults.
+func NewSummarizingWindow(tokenLimit int, summarizer Summarizer) *SummarizingWindow {
+	return &SummarizingWindow{
+		TokenLimit:  tokenLimit,
+		TailBudget:  tokenLimit * 3 / 4,
+		CountTokens: DefaultTokenCounter,
+		Summarizer:  summarizer,
+	}
+}
+
+// Apply implements WindowPolicy.
+func (w *SummarizingWindow) Apply(ctx context.Context, items []result.RunItem) ([]result.RunItem, error) {
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	counter := w.CountTokens
+	if counter == nil {
+		counter = DefaultTokenCounter
+	}
+
+	tailBudget := w.TailBudget
+	if tailBudget <= 0 {
+		tailBudget = w.TokenLimit * 3 / 4
+	}
+
+	// Walk backward from the most recent item, keeping items verbatim until
+	// the tail budget is exhausted. A previously synthesized SummaryItem
+	// reached before then is treated as the start of the tail, so it is not
+	// re-summarized.
+	splitIndex := len(items)
+	tailTokens := 0
+	for splitIndex > 0 {
+		candidate := items[splitIndex-1]
+		if _, ok := candidate.(*result.SummaryItem); ok {
+			splitIndex--
+			break
+		}
+
+		cost := counter(candidate)
+		if tailTokens > 0 && tailTokens+cost > tailBudget {
+			break
+		}
+		tailTokens += cost
+		splitIndex--
+	}
+
+	older := items[:splitIndex]
+	tail := items[splitIndex:]
+
+	if len(older) == 0 {
+		return items, nil
+	}
+
+	totalTokens := tailTokens
+	for _, item := range older {
+		totalTokens += counter(item)
+	}
+	if totalTokens <= w.TokenLimit {
+		return items, nil
+	}
+
+	if w.Summarizer == nil {
+		return nil, fmt.Errorf("memory: window needs %d tokens but limit is %d and no Summarizer is configured", totalTokens, w.TokenLimit)
+	}
+
+	summary, err := w.Summarizer.Summarize(ctx, older)
+	if err != nil {
+		return nil, fmt.Errorf("memory: failed to summarize older window items: %w", err)
+	}
+
+	compacted := make([]result.RunItem, 0, len(tail)+1)
+	compacted = append(compacted, &result.MessageItem{Role: "system", Content: summary})
+	compacted = append(compacted, tail...)
+	return compacted, nil
+}
+
+// persistCompactedWindow rewrites sessionID's stored history to match a
+// compacted window returned by a WindowPolicy. The synthesized system
+// message standing in for the summarized items is stored as a
+// result.SummaryItem instead, so later windowing passes recognize it as
+// already summarized rather than folding it back into plain message history.
+func persistCompactedWindow(ctx context.Context, storage Memory, sessionID string, original, compacted []result.RunItem) error {
+	if len(compacted) >= len(original) {
+		return nil // policy made no changes, nothing to persist
+	}
+
+	stable := make([]result.RunItem, len(compacted))
+	if msg, ok := compacted[0].(*result.MessageItem); ok && msg.Role == "system" {
+		stable[0] = &result.SummaryItem{Content: msg.Content}
+	} else {
+		stable[0] = compacted[0]
+	}
+	copy(stable[1:], compacted[1:])
+
+	if err := storage.Clear(ctx, sessionID); err != nil {
+		return fmt.Errorf("memory: failed to persist compacted window: %w", err)
+	}
+	if err := storage.Add(ctx, sessionID, &result.RunResult{NewItems: stable}); err != nil {
+		return fmt.Errorf("memory: failed to persist compacted window: %w", err)
+	}
+	return nil
+}
+
+// asSystemMessage renders a persisted result.SummaryItem back into the
+// result.MessageItem{Role: "system"} form a WindowPolicy and model.Request
+// expect, so a summary already on disk from an earlier GetWindowed call
+// looks the same to the caller as one just produced by Apply. Other item
+// types pass through unchanged.
+func asSystemMessage(item result.RunItem) result.RunItem {
+	if summary, ok := item.(*result.SummaryItem); ok {
+		return &result.MessageItem{Role: "system", Content: summary.Content}
+	}
+	return item
+}
+
+// normalizeWindow applies asSystemMessage to every item in items, so a
+// WindowPolicy's output is stable across calls regardless of whether the
+// leading summary came from Apply just now or was reloaded from storage.
+func normalizeWindow(items []result.RunItem) []result.RunItem {
+	normalized := make([]result.RunItem, len(items))
+	for i, item := range items {
+		normalized[i] = asSystemMessage(item)
+	}
+	return normalized
+}
+
+// GetWindowed returns sessionID's history compacted under policy. When the
+// policy folds older items into a summary, the compacted history is written
+// back to the session so subsequent calls don't redo that work.
+func (m *InMemoryStorage) GetWindowed(ctx context.Context, sessionID string, policy WindowPolicy) ([]result.RunItem, error) {
+	items, err := m.GetAll(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, err := policy.Apply(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := persistCompactedWindow(ctx, m, sessionID, items, compacted); err != nil {
+		return nil, err
+	}
+
+	return normalizeWindow(compacted), nil
+}
+
+// GetWindowed returns sessionID's history compacted under policy. When the
+// policy folds older items into a summary, the compacted history is written
+// back to the session so subsequent calls don't redo that work.
+func (b *BoltStorage) GetWindowed(ctx context.Context, sessionID string, policy WindowPolicy) ([]result.RunItem, error) {
+	items, err := b.GetAll(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, err := policy.Apply(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := persistCompactedWindow(ctx, b, sessionID, items, compacted); err != nil {
+		return nil, err
+	}
+
+	return normalizeWindow(compacted), nil
+}
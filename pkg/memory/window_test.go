@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+)
+
+func countingSummarizer(calls *int) Summarizer {
+	return SummarizerFunc(func(ctx context.Context, items []result.RunItem) (string, error) {
+		*calls++
+		var parts []string
+		for _, item := range items {
+			parts = append(parts, itemText(item))
+		}
+		return "summary of: " + strings.Join(parts, "; "), nil
+	})
+}
+
+func TestSummarizingWindow_KeepsEverythingUnderLimit(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+
+	items := []result.RunItem{
+		&result.MessageItem{Role: "user", Content: "hi"},
+		&result.MessageItem{Role: "assistant", Content: "hello"},
+	}
+
+	window := NewSummarizingWindow(10_000, countingSummarizer(&calls))
+	out, err := window.Apply(ctx, items)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(out) != len(items) {
+		t.Errorf("Expected short history to pass through unchanged, got %d items", len(out))
+	}
+	if calls != 0 {
+		t.Errorf("Expected Summarizer not to be called, got %d calls", calls)
+	}
+}
+
+func TestSummarizingWindow_CompactsOlderItems(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+
+	var items []result.RunItem
+	for i := 0; i < 50; i++ {
+		items = append(items, &result.MessageItem{
+			Role:    "user",
+			Content: strings.Repeat("word ", 40),
+		})
+	}
+
+	window := NewSummarizingWindow(200, countingSummarizer(&calls))
+	out, err := window.Apply(ctx, items)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected Summarizer to be called once, got %d calls", calls)
+	}
+	if len(out) >= len(items) {
+		t.Fatalf("Expected compacted output to be shorter than input (%d), got %d", len(items), len(out))
+	}
+
+	summaryMsg, ok := out[0].(*result.MessageItem)
+	if !ok || summaryMsg.Role != "system" {
+		t.Fatalf("Expected first item to be a system summary message, got %+v", out[0])
+	}
+}
+
+func TestSummarizingWindow_NeverReSummarizesExistingSummary(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+
+	items := []result.RunItem{
+		&result.SummaryItem{Content: "earlier conversation summary"},
+		&result.MessageItem{Role: "user", Content: "new message"},
+	}
+
+	window := NewSummarizingWindow(10_000, countingSummarizer(&calls))
+	out, err := window.Apply(ctx, items)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected Summarizer not to be called when total is under budget, got %d calls", calls)
+	}
+	if len(out) != 2 {
+		t.Fatalf("Expected both items preserved, got %d", len(out))
+	}
+	if _, ok := out[0].(*result.SummaryItem); !ok {
+		t.Errorf("Expected existing SummaryItem to be preserved as-is, got %T", out[0])
+	}
+}
+
+func TestInMemoryStorage_GetWindowedPersistsSummary(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	sessionID := "test-session"
+
+	m := NewInMemoryStorage()
+	var items []result.RunItem
+	for i := 0; i < 50; i++ {
+		items = append(items, &result.MessageItem{Role: "user", Content: strings.Repeat("word ", 40)})
+	}
+	if err := m.Add(ctx, sessionID, &result.RunResult{NewItems: items}); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	window := NewSummarizingWindow(200, countingSummarizer(&calls))
+
+	first, err := m.GetWindowed(ctx, sessionID, window)
+	if err != nil {
+		t.Fatalf("GetWindowed failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected one summarization call, got %d", calls)
+	}
+
+	// The compacted history should now be what's stored, so a second
+	// GetWindowed call over the same (already compact) history should not
+	// need to summarize again.
+	second, err := m.GetWindowed(ctx, sessionID, window)
+	if err != nil {
+		t.Fatalf("Second GetWindowed failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no additional summarization calls, got %d total", calls)
+	}
+	if len(second) != len(first) {
+		t.Errorf("Expected stable windowed history, got %d then %d items", len(first), len(second))
+	}
+
+	stored, err := m.GetAll(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("Failed to get stored items: %v", err)
+	}
+	if _, ok := stored[0].(*result.SummaryItem); !ok {
+		t.Errorf("Expected session history to be rewritten with a SummaryItem, got %T", stored[0])
+	}
+
+	// Although the persisted form is a SummaryItem, GetWindowed must keep
+	// handing back a system MessageItem on every call, since that's what a
+	// model.Request history expects; only storage sees the SummaryItem.
+	secondSummaryMsg, ok := second[0].(*result.MessageItem)
+	if !ok || secondSummaryMsg.Role != "system" {
+		t.Errorf("Expected second GetWindowed call to return a system MessageItem summary, got %T", second[0])
+	}
+}
+
+func TestBoltStorage_GetWindowedPersistsSummary(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	sessionID := "test-session"
+	dbPath := filepath.Join(t.TempDir(), "memory.db")
+
+	storage, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create bolt storage: %v", err)
+	}
+	defer storage.Close()
+
+	var items []result.RunItem
+	for i := 0; i < 50; i++ {
+		items = append(items, &result.MessageItem{Role: "user", Content: strings.Repeat("word ", 40)})
+	}
+	if err := storage.Add(ctx, sessionID, &result.RunResult{NewItems: items}); err != nil {
+		t.Fatalf("Failed to add to memory: %v", err)
+	}
+
+	window := NewSummarizingWindow(200, countingSummarizer(&calls))
+
+	first, err := storage.GetWindowed(ctx, sessionID, window)
+	if err != nil {
+		t.Fatalf("GetWindowed failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected one summarization call, got %d", calls)
+	}
+
+	// Round-tripping the persisted SummaryItem through BoltDB must not
+	// break subsequent reads: a second GetWindowed call (and a plain
+	// GetAll) over the already-compacted history must still decode fine
+	// and must not trigger another summarization.
+	second, err := storage.GetWindowed(ctx, sessionID, window)
+	if err != nil {
+		t.Fatalf("Second GetWindowed failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no additional summarization calls, got %d total", calls)
+	}
+	if len(second) != len(first) {
+		t.Errorf("Expected stable windowed history, got %d then %d items", len(first), len(second))
+	}
+
+	stored, err := storage.GetAll(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("Failed to get stored items after persisting a summary: %v", err)
+	}
+	if len(stored) == 0 {
+		t.Fatal("Expected stored items after persisting a summary, got none")
+	}
+	if _, ok := stored[0].(*result.SummaryItem); !ok {
+		t.Errorf("Expected session history to be rewritten with a SummaryItem, got %T", stored[0])
+	}
+}
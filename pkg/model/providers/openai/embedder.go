@@ -0,0 +1,105 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// EmbeddingModel is the default OpenAI embeddings model used by Embedder.
+const EmbeddingModel = "text-embedding-3-small"
+
+const defaultEmbeddingsURL = "https://api.openai.com/v1/embeddings"
+
+// Embedder implements memory.Embedder on top of OpenAI's embeddings API, so
+// a memory.HybridStorage can be backed by real semantic recall rather than
+// a test double.
+type Embedder struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewEmbedder creates an Embedder that calls OpenAI's EmbeddingModel using
+// apiKey.
+func NewEmbedder(apiKey string) *Embedder {
+	return &Embedder{
+		apiKey:  apiKey,
+		model:   EmbeddingModel,
+		baseURL: defaultEmbeddingsURL,
+		client:  http.DefaultClient,
+	}
+}
+
+// WithModel overrides the embeddings model used, e.g. to
+// "text-embedding-3-large".
+func (e *Embedder) WithModel(model string) *Embedder {
+	e.model = model
+	return e
+}
+
+// WithBaseURL overrides the embeddings endpoint, e.g. to target an
+// Azure OpenAI deployment or a test server.
+func (e *Embedder) WithBaseURL(baseURL string) *Embedder {
+	e.baseURL = baseURL
+	return e
+}
+
+// WithHTTPClient overrides the HTTP client used to call the embeddings
+// endpoint. Defaults to http.DefaultClient.
+func (e *Embedder) WithHTTPClient(client *http.Client) *Embedder {
+	e.client = client
+	return e
+}
+
+type embeddingsRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed implements memory.Embedder by requesting a single embedding for
+// text from the OpenAI embeddings endpoint.
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	body, err := json.Marshal(embeddingsRequest{Model: e.model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embeddings request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embeddings request returned status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var parsed embeddingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+	if len(parsed.Data) == 0 {
+		return nil, fmt.Errorf("embeddings response contained no data")
+	}
+
+	return parsed.Data[0].Embedding, nil
+}
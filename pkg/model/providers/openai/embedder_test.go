@@ -0,0 +1,60 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEmbedder_EmbedReturnsVector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Expected Authorization header 'Bearer test-key', got %q", got)
+		}
+
+		var req embeddingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if req.Model != EmbeddingModel {
+			t.Errorf("Expected model %q, got %q", EmbeddingModel, req.Model)
+		}
+		if req.Input != "hello world" {
+			t.Errorf("Expected input %q, got %q", "hello world", req.Input)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(embeddingsResponse{
+			Data: []struct {
+				Embedding []float32 `json:"embedding"`
+			}{{Embedding: []float32{0.1, 0.2, 0.3}}},
+		})
+	}))
+	defer server.Close()
+
+	embedder := NewEmbedder("test-key").WithBaseURL(server.URL)
+
+	vec, err := embedder.Embed(context.Background(), "hello world")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(vec) != 3 || vec[0] != 0.1 || vec[1] != 0.2 || vec[2] != 0.3 {
+		t.Errorf("Expected [0.1 0.2 0.3], got %v", vec)
+	}
+}
+
+func TestEmbedder_EmbedReturnsErrorOnNonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid api key"}`))
+	}))
+	defer server.Close()
+
+	embedder := NewEmbedder("bad-key").WithBaseURL(server.URL)
+
+	if _, err := embedder.Embed(context.Background(), "hello"); err == nil {
+		t.Fatal("Expected an error for a non-OK response, got nil")
+	}
+}
@@ -0,0 +1,16 @@
+package result
+
+// SummaryItem is a RunItem produced by memory.WindowPolicy implementations
+// (e.g. memory.SummarizingWindow) to stand in for older history that has
+// been folded into a single synthesized summary. It is stored as its own
+// discriminated type, distinct from MessageItem, so a policy can recognize
+// previously summarized history and avoid re-summarizing it on later calls.
+type SummaryItem struct {
+	// Content is the synthesized summary text.
+	Content string `json:"content"`
+}
+
+// GetType implements RunItem.
+func (s *SummaryItem) GetType() string {
+	return "summary"
+}
@@ -0,0 +1,215 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/agent"
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/memory"
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/model"
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/tool"
+)
+
+// defaultMaxTurns bounds the tool-call loop in RunSync when
+// RunOptions.MaxTurns is left at its zero value.
+const defaultMaxTurns = 3
+
+// RunOptions configures a single RunSync call.
+type RunOptions struct {
+	// Input is the user's message for this turn.
+	Input string
+
+	// SessionID identifies the conversation in the configured Memory. If
+	// empty, no history is loaded or persisted.
+	SessionID string
+
+	// MaxTurns bounds how many tool-call round trips the agent may take
+	// before RunSync gives up and returns its last output. Defaults to
+	// defaultMaxTurns when zero.
+	MaxTurns int
+}
+
+// Runner drives an agent.Agent through a turn against a model.Provider,
+// optionally backed by a Memory for multi-turn history, windowing, and
+// semantic recall.
+type Runner struct {
+	defaultProvider model.Provider
+	mem             memory.Memory
+	windowPolicy    memory.WindowPolicy
+	recallK         int
+}
+
+// NewRunner creates a Runner with no provider or memory configured.
+func NewRunner() *Runner {
+	return &Runner{}
+}
+
+// WithDefaultProvider sets the model.Provider used for agents that don't
+// set their own.
+func (r *Runner) WithDefaultProvider(provider model.Provider) *Runner {
+	r.defaultProvider = provider
+	return r
+}
+
+// WithMemory attaches a Memory so RunSync can load and persist session
+// history.
+func (r *Runner) WithMemory(mem memory.Memory) *Runner {
+	r.mem = mem
+	return r
+}
+
+// WithMemoryWindow configures policy to compact a session's history before
+// it is replayed to the model. It applies via memory.Windowed when the
+// configured Memory supports it (e.g. InMemoryStorage, BoltStorage), so the
+// compaction is persisted; otherwise RunSync applies policy directly to the
+// history it reads, without persisting the result.
+func (r *Runner) WithMemoryWindow(policy memory.WindowPolicy) *Runner {
+	r.windowPolicy = policy
+	return r
+}
+
+// WithRecall enables semantic recall: each turn, the top k items most
+// relevant to the user's input are fetched from the configured Memory (via
+// memory.Recaller, e.g. HybridStorage) and injected as system context
+// alongside the recent window. It has no effect if the configured Memory
+// doesn't implement memory.Recaller.
+func (r *Runner) WithRecall(k int) *Runner {
+	r.recallK = k
+	return r
+}
+
+// RunSync runs ag once against opts.Input and returns the result, loading
+// and persisting history in the configured Memory if any.
+func (r *Runner) RunSync(ag *agent.Agent, opts *RunOptions) (*result.RunResult, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("run options cannot be nil")
+	}
+
+	ctx := context.Background()
+
+	provider := ag.ModelProvider()
+	if provider == nil {
+		provider = r.defaultProvider
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("no model provider configured for agent %q", ag.Name())
+	}
+
+	recalled, err := r.recalledItems(ctx, opts.SessionID, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recall related items: %w", err)
+	}
+
+	history, err := r.sessionHistory(ctx, opts.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session history: %w", err)
+	}
+
+	maxTurns := opts.MaxTurns
+	if maxTurns <= 0 {
+		maxTurns = defaultMaxTurns
+	}
+
+	tools := ag.Tools()
+	toolsByName := make(map[string]tool.Tool, len(tools))
+	for _, t := range tools {
+		toolsByName[t.Name()] = t
+	}
+
+	conversation := make([]result.RunItem, 0, len(recalled)+len(history))
+	conversation = append(conversation, recalled...)
+	conversation = append(conversation, history...)
+
+	newItems := []result.RunItem{&result.MessageItem{Role: "user", Content: opts.Input}}
+
+	var finalOutput string
+	for turn := 0; turn < maxTurns; turn++ {
+		resp, err := provider.GetResponse(ctx, &model.Request{
+			Model:        ag.Model(),
+			Instructions: ag.Instructions(),
+			History:      conversation,
+			Input:        opts.Input,
+			Tools:        tools,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("model call failed: %w", err)
+		}
+		finalOutput = resp.Output
+
+		if len(resp.ToolCalls) == 0 {
+			break
+		}
+
+		for _, call := range resp.ToolCalls {
+			callItem := &result.ToolCallItem{Name: call.Name, Parameters: call.Parameters}
+			conversation = append(conversation, callItem)
+			newItems = append(newItems, callItem)
+
+			t, ok := toolsByName[call.Name]
+			var output interface{}
+			if !ok {
+				output = fmt.Sprintf("error: unknown tool %q", call.Name)
+			} else if out, execErr := t.Execute(ctx, call.Parameters); execErr != nil {
+				output = fmt.Sprintf("error: %v", execErr)
+			} else {
+				output = out
+			}
+
+			resultItem := &result.ToolResultItem{Name: call.Name, Result: output}
+			conversation = append(conversation, resultItem)
+			newItems = append(newItems, resultItem)
+		}
+	}
+
+	runResult := &result.RunResult{
+		Input:       opts.Input,
+		NewItems:    newItems,
+		FinalOutput: finalOutput,
+	}
+
+	if r.mem != nil && opts.SessionID != "" {
+		if err := r.mem.Add(ctx, opts.SessionID, runResult); err != nil {
+			return nil, fmt.Errorf("failed to persist run result: %w", err)
+		}
+	}
+
+	return runResult, nil
+}
+
+// sessionHistory loads sessionID's history, applying r.windowPolicy when one
+// is configured.
+func (r *Runner) sessionHistory(ctx context.Context, sessionID string) ([]result.RunItem, error) {
+	if r.mem == nil || sessionID == "" {
+		return nil, nil
+	}
+
+	if r.windowPolicy == nil {
+		return r.mem.Get(ctx, sessionID, nil)
+	}
+
+	if windowed, ok := r.mem.(memory.Windowed); ok {
+		return windowed.GetWindowed(ctx, sessionID, r.windowPolicy)
+	}
+
+	items, err := r.mem.Get(ctx, sessionID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.windowPolicy.Apply(ctx, items)
+}
+
+// recalledItems fetches the top r.recallK semantically relevant items for
+// query, when recall is enabled and the configured Memory supports it.
+func (r *Runner) recalledItems(ctx context.Context, sessionID, query string) ([]result.RunItem, error) {
+	if r.mem == nil || sessionID == "" || r.recallK <= 0 {
+		return nil, nil
+	}
+
+	recaller, ok := r.mem.(memory.Recaller)
+	if !ok {
+		return nil, nil
+	}
+
+	return recaller.Recall(ctx, sessionID, query, r.recallK)
+}
@@ -0,0 +1,223 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/agent"
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/memory"
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/model"
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/result"
+	"github.com/pontus-devoteam/agent-sdk-go/pkg/tool"
+)
+
+// recordingProvider is a fake model.Provider that records the request it
+// was asked to answer, so tests can assert on what the runner actually sent
+// to the model without making a real API call.
+type recordingProvider struct {
+	lastRequest *model.Request
+	output      string
+}
+
+func (p *recordingProvider) GetResponse(ctx context.Context, req *model.Request) (*model.Response, error) {
+	p.lastRequest = req
+	return &model.Response{Output: p.output}, nil
+}
+
+// scriptedToolCallProvider is a fake model.Provider that requests toolCall
+// on its first GetResponse call and returns output on every call after,
+// letting tests drive RunSync through a tool-call round trip.
+type scriptedToolCallProvider struct {
+	toolCall model.ToolCall
+	output   string
+
+	requests []*model.Request
+}
+
+func (p *scriptedToolCallProvider) GetResponse(ctx context.Context, req *model.Request) (*model.Response, error) {
+	p.requests = append(p.requests, req)
+	if len(p.requests) == 1 {
+		return &model.Response{ToolCalls: []model.ToolCall{p.toolCall}}, nil
+	}
+	return &model.Response{Output: p.output}, nil
+}
+
+func countingSummarizer(calls *int) memory.Summarizer {
+	return memory.SummarizerFunc(func(ctx context.Context, items []result.RunItem) (string, error) {
+		*calls++
+		return "summary", nil
+	})
+}
+
+func TestRunner_WithMemoryWindowCompactsBeforeModelCall(t *testing.T) {
+	ctx := context.Background()
+	sessionID := "alice-session"
+
+	mem := memory.NewInMemoryStorage()
+	var seed []result.RunItem
+	for i := 0; i < 50; i++ {
+		seed = append(seed, &result.MessageItem{Role: "user", Content: strings.Repeat("word ", 40)})
+	}
+	if err := mem.Add(ctx, sessionID, &result.RunResult{NewItems: seed}); err != nil {
+		t.Fatalf("Failed to seed memory: %v", err)
+	}
+
+	calls := 0
+	window := memory.NewSummarizingWindow(200, countingSummarizer(&calls))
+
+	provider := &recordingProvider{output: "hi there"}
+	ag := agent.NewAgent("ChatBot")
+	ag.SetModelProvider(provider)
+	ag.WithModel("test-model")
+
+	r := NewRunner()
+	r.WithDefaultProvider(provider)
+	r.WithMemory(mem)
+	r.WithMemoryWindow(window)
+
+	if _, err := r.RunSync(ag, &RunOptions{Input: "what did we talk about?", SessionID: sessionID}); err != nil {
+		t.Fatalf("RunSync failed: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("Expected the window policy to summarize once before the model call, got %d calls", calls)
+	}
+	if provider.lastRequest == nil {
+		t.Fatal("Expected the provider to receive a request")
+	}
+	if len(provider.lastRequest.History) >= len(seed) {
+		t.Errorf("Expected compacted history (< %d items) sent to the model, got %d", len(seed), len(provider.lastRequest.History))
+	}
+}
+
+func TestRunner_WithoutMemoryWindowReplaysFullHistory(t *testing.T) {
+	ctx := context.Background()
+	sessionID := "bob-session"
+
+	mem := memory.NewInMemoryStorage()
+	if err := mem.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "hello"}},
+	}); err != nil {
+		t.Fatalf("Failed to seed memory: %v", err)
+	}
+
+	provider := &recordingProvider{output: "hi"}
+	ag := agent.NewAgent("ChatBot")
+	ag.SetModelProvider(provider)
+
+	r := NewRunner()
+	r.WithDefaultProvider(provider)
+	r.WithMemory(mem)
+
+	if _, err := r.RunSync(ag, &RunOptions{Input: "how are you?", SessionID: sessionID}); err != nil {
+		t.Fatalf("RunSync failed: %v", err)
+	}
+
+	if len(provider.lastRequest.History) != 1 {
+		t.Errorf("Expected the full unwindowed history (1 item), got %d", len(provider.lastRequest.History))
+	}
+}
+
+func TestRunner_WithRecallInjectsSemanticMatches(t *testing.T) {
+	ctx := context.Background()
+	sessionID := "carol-session"
+
+	base := memory.NewInMemoryStorage()
+	index := memory.NewInMemoryVectorIndex(memory.EmbedderFunc(func(ctx context.Context, text string) ([]float32, error) {
+		if strings.Contains(strings.ToLower(text), "favorite color") {
+			return []float32{1, 0}, nil
+		}
+		return []float32{0, 1}, nil
+	}))
+	hybrid := memory.NewHybridStorage(base, index)
+
+	if err := hybrid.Add(ctx, sessionID, &result.RunResult{
+		NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "my favorite color is green"}},
+	}); err != nil {
+		t.Fatalf("Failed to seed memory: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := hybrid.Add(ctx, sessionID, &result.RunResult{
+			NewItems: []result.RunItem{&result.MessageItem{Role: "user", Content: "unrelated filler turn"}},
+		}); err != nil {
+			t.Fatalf("Failed to add filler turn %d: %v", i, err)
+		}
+	}
+
+	provider := &recordingProvider{output: "green, you told me"}
+	ag := agent.NewAgent("ChatBot")
+	ag.SetModelProvider(provider)
+
+	r := NewRunner()
+	r.WithDefaultProvider(provider)
+	r.WithMemory(hybrid)
+	r.WithRecall(1)
+
+	if _, err := r.RunSync(ag, &RunOptions{Input: "what's my favorite color?", SessionID: sessionID}); err != nil {
+		t.Fatalf("RunSync failed: %v", err)
+	}
+
+	found := false
+	for _, item := range provider.lastRequest.History {
+		if msg, ok := item.(*result.MessageItem); ok && strings.Contains(msg.Content, "favorite color is green") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected recalled item about favorite color in request history, got %+v", provider.lastRequest.History)
+	}
+}
+
+func TestRunner_RunsToolCallLoopAndFeedsResultsBack(t *testing.T) {
+	sessionID := "dave-session"
+
+	getTimeInfo := tool.NewFunctionTool(
+		"get_time_info",
+		"Get current time information",
+		func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return "2026-07-29T00:00:00Z", nil
+		},
+	)
+
+	provider := &scriptedToolCallProvider{
+		toolCall: model.ToolCall{Name: "get_time_info", Parameters: map[string]interface{}{}},
+		output:   "it's 2026-07-29T00:00:00Z",
+	}
+	ag := agent.NewAgent("ChatBot")
+	ag.SetModelProvider(provider)
+	ag.WithTools(getTimeInfo)
+
+	r := NewRunner()
+	r.WithDefaultProvider(provider)
+
+	runResult, err := r.RunSync(ag, &RunOptions{Input: "what time is it?", SessionID: sessionID, MaxTurns: 3})
+	if err != nil {
+		t.Fatalf("RunSync failed: %v", err)
+	}
+
+	if len(provider.requests) != 2 {
+		t.Fatalf("Expected the runner to call the provider twice (tool call, then final answer), got %d", len(provider.requests))
+	}
+	if len(provider.requests[0].Tools) != 1 || provider.requests[0].Tools[0].Name() != "get_time_info" {
+		t.Fatalf("Expected the agent's tools to be passed to the model, got %+v", provider.requests[0].Tools)
+	}
+
+	foundResult := false
+	for _, item := range provider.requests[1].History {
+		if toolResult, ok := item.(*result.ToolResultItem); ok && toolResult.Name == "get_time_info" {
+			foundResult = true
+			if toolResult.Result != "2026-07-29T00:00:00Z" {
+				t.Errorf("Expected tool result fed back to the model, got %v", toolResult.Result)
+			}
+		}
+	}
+	if !foundResult {
+		t.Errorf("Expected the second request's history to include the tool's result, got %+v", provider.requests[1].History)
+	}
+
+	if runResult.FinalOutput != "it's 2026-07-29T00:00:00Z" {
+		t.Errorf("Expected the final answer after the tool call, got %q", runResult.FinalOutput)
+	}
+}